@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// JobQueueAPI exposes a JobQueue over HTTP: POST /analyze to enqueue,
+// GET /jobs/{id} for status, GET /jobs/{id}/events for a Server-Sent
+// Events progress stream, and DELETE /jobs/{id} to cancel.
+type JobQueueAPI struct {
+	queue *JobQueue
+}
+
+// NewJobQueueAPI wraps queue for serving over HTTP.
+func NewJobQueueAPI(queue *JobQueue) *JobQueueAPI {
+	return &JobQueueAPI{queue: queue}
+}
+
+// ListenAndServe starts the API on addr. It blocks until the server
+// returns an error.
+func (a *JobQueueAPI) ListenAndServe(addr string) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/analyze", a.handleAnalyze)
+	mux.HandleFunc("/jobs/", a.handleJob)
+
+	return http.ListenAndServe(addr, mux)
+}
+
+func (a *JobQueueAPI) handleAnalyze(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req JobRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("invalid request body: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	job := a.queue.Enqueue(req)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]string{"job_id": job.ID})
+}
+
+// handleJob dispatches /jobs/{id} and /jobs/{id}/events.
+func (a *JobQueueAPI) handleJob(w http.ResponseWriter, r *http.Request) {
+	rest := strings.TrimPrefix(r.URL.Path, "/jobs/")
+	id, sub, hasSub := strings.Cut(rest, "/")
+
+	job, ok := a.queue.Get(id)
+	if !ok {
+		http.Error(w, "job not found", http.StatusNotFound)
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodDelete && !hasSub:
+		a.queue.Cancel(id)
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodGet && !hasSub:
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(a.queue.Snapshot(job))
+	case r.Method == http.MethodGet && sub == "events":
+		a.streamEvents(w, job)
+	default:
+		http.Error(w, "not found", http.StatusNotFound)
+	}
+}
+
+func (a *JobQueueAPI) streamEvents(w http.ResponseWriter, job *Job) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	events, ch, cancel := a.queue.Subscribe(job)
+	defer cancel()
+
+	for _, event := range events {
+		writeSSE(w, event)
+	}
+	flusher.Flush()
+
+	for event := range ch {
+		writeSSE(w, event)
+		flusher.Flush()
+	}
+}
+
+func writeSSE(w http.ResponseWriter, event JobEvent) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Step, data)
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bufio"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// imageMaskFor returns the release mask to use for pack in the project at
+// path: a per-pack override from starter.toml if present, otherwise
+// --image-mask.
+func imageMaskFor(path, packName string) string {
+	if mask, ok := starterTomlImageMask(filepath.Join(path, "starter.toml"), packName); ok {
+		return mask
+	}
+	return flagImageMask
+}
+
+// starterTomlImageMask reads a minimal subset of starter.toml: a top-level
+// [pack.<name>] section with an image_mask = "..." key. This intentionally
+// doesn't pull in a full TOML parser, just enough to let projects pin a
+// mask per pack without a registry flag on every run.
+func starterTomlImageMask(tomlPath, packName string) (string, bool) {
+	file, err := os.Open(tomlPath)
+	if err != nil {
+		return "", false
+	}
+	defer file.Close()
+
+	wantSection := "[pack." + packName + "]"
+	inSection := false
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			inSection = line == wantSection
+			continue
+		}
+
+		if !inSection {
+			continue
+		}
+
+		key, value, ok := splitVarFlag(strings.ReplaceAll(line, " ", ""))
+		if ok && key == "image_mask" {
+			return strings.Trim(value, `"`), true
+		}
+	}
+
+	return "", false
+}
+
+// sampleStrings returns n values picked at random from values, without
+// replacement and without mutating the input slice.
+func sampleStrings(values []string, n int) []string {
+	shuffled := make([]string, len(values))
+	copy(shuffled, values)
+	rand.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+	return shuffled[:n]
+}
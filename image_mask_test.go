@@ -0,0 +1,66 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStarterTomlImageMask(t *testing.T) {
+	dir := t.TempDir()
+	tomlPath := filepath.Join(dir, "starter.toml")
+	contents := `[pack.rails]
+image_mask = "^2\\.7\\..*$"
+
+[pack.node]
+other_key = "ignored"
+`
+	if err := os.WriteFile(tomlPath, []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	mask, ok := starterTomlImageMask(tomlPath, "rails")
+	if !ok || mask != `^2\.7\..*$` {
+		t.Fatalf("got mask=%q ok=%v, want %q/true", mask, ok, `^2\.7\..*$`)
+	}
+
+	if _, ok := starterTomlImageMask(tomlPath, "node"); ok {
+		t.Fatal("expected no image_mask for [pack.node], got one")
+	}
+
+	if _, ok := starterTomlImageMask(tomlPath, "missing"); ok {
+		t.Fatal("expected no image_mask for an absent section")
+	}
+}
+
+func TestImageMaskForFallsBackToFlag(t *testing.T) {
+	old := flagImageMask
+	flagImageMask = `^\d+\.\d+\.\d+$`
+	defer func() { flagImageMask = old }()
+
+	dir := t.TempDir()
+	if got := imageMaskFor(dir, "rails"); got != flagImageMask {
+		t.Fatalf("got %q, want fallback %q", got, flagImageMask)
+	}
+}
+
+func TestSampleStrings(t *testing.T) {
+	values := []string{"a", "b", "c", "d", "e"}
+
+	sample := sampleStrings(values, 3)
+	if len(sample) != 3 {
+		t.Fatalf("got %d values, want 3", len(sample))
+	}
+
+	seen := map[string]bool{}
+	for _, v := range sample {
+		seen[v] = true
+	}
+	if len(seen) != 3 {
+		t.Fatalf("sample has duplicates: %v", sample)
+	}
+
+	if len(values) != 5 || values[0] != "a" {
+		t.Fatal("sampleStrings mutated its input")
+	}
+}
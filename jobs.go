@@ -0,0 +1,362 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/cloud66-oss/starter/common"
+)
+
+// JobStatus is the lifecycle state of a single analysis job.
+type JobStatus string
+
+const (
+	JobQueued    JobStatus = "queued"
+	JobRunning   JobStatus = "running"
+	JobDone      JobStatus = "done"
+	JobFailed    JobStatus = "failed"
+	JobCancelled JobStatus = "cancelled"
+)
+
+func isTerminal(status JobStatus) bool {
+	switch status {
+	case JobDone, JobFailed, JobCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// JobRequest is the body of POST /analyze. Multi drives a component's worth
+// of a monorepo through analyzeMulti instead of the single-pack analyze(),
+// mirroring the CLI's -multi flag so API callers can detect and analyze a
+// monorepo's components the same way the CLI does.
+type JobRequest struct {
+	Path        string `json:"path"`
+	Environment string `json:"environment"`
+	Generator   string `json:"generator"`
+	Overwrite   bool   `json:"overwrite"`
+	UseRegistry bool   `json:"use_registry"`
+	Multi       bool   `json:"multi"`
+}
+
+// JobEvent is a single step emitted while a job runs, streamed to
+// GET /jobs/{id}/events and also kept on Job.Events for replay.
+type JobEvent struct {
+	Step    string    `json:"step"`
+	Message string    `json:"message"`
+	At      time.Time `json:"at"`
+}
+
+// Job tracks one enqueued analysis request end to end.
+type Job struct {
+	ID        string            `json:"id"`
+	Status    JobStatus         `json:"status"`
+	Request   JobRequest        `json:"request"`
+	Result    *analysisResult   `json:"result,omitempty"`
+	Results   []*analysisResult `json:"results,omitempty"`
+	Error     string            `json:"error,omitempty"`
+	Events    []JobEvent        `json:"events"`
+	CreatedAt time.Time         `json:"created_at"`
+
+	cancel      chan struct{}
+	cancelOnce  sync.Once
+	subscribers []chan JobEvent
+}
+
+// JobView is a point-in-time, lock-free copy of a Job's externally visible
+// state. Job itself must never cross goroutines directly (its fields are
+// written by the worker goroutine under JobQueue.mu) — callers in another
+// goroutine, such as the HTTP handlers in api_jobs.go, should read a Job
+// through JobQueue.Snapshot instead.
+type JobView struct {
+	ID        string            `json:"id"`
+	Status    JobStatus         `json:"status"`
+	Request   JobRequest        `json:"request"`
+	Result    *analysisResult   `json:"result,omitempty"`
+	Results   []*analysisResult `json:"results,omitempty"`
+	Error     string            `json:"error,omitempty"`
+	Events    []JobEvent        `json:"events"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// JobQueue runs enqueued jobs on a bounded worker pool and persists job
+// state to disk so the daemon can be queried (or restarted) without losing
+// history.
+type JobQueue struct {
+	workspaceDir string
+	concurrency  int
+
+	mu      sync.Mutex
+	jobs    map[string]*Job
+	pending chan *Job
+	stop    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// NewJobQueue creates a queue backed by workspaceDir/jobs, with up to
+// concurrency jobs running at once.
+func NewJobQueue(workspaceDir string, concurrency int) (*JobQueue, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	jobsDir := filepath.Join(workspaceDir, "jobs")
+	if err := os.MkdirAll(jobsDir, 0755); err != nil {
+		return nil, err
+	}
+
+	return &JobQueue{
+		workspaceDir: workspaceDir,
+		concurrency:  concurrency,
+		jobs:         map[string]*Job{},
+		pending:      make(chan *Job, 128),
+		stop:         make(chan struct{}),
+	}, nil
+}
+
+// Start launches the worker pool.
+func (q *JobQueue) Start() {
+	for i := 0; i < q.concurrency; i++ {
+		q.wg.Add(1)
+		go q.worker()
+	}
+}
+
+// Stop signals every worker to finish its current job and return.
+func (q *JobQueue) Stop() {
+	close(q.stop)
+	q.wg.Wait()
+}
+
+// Enqueue creates a new job for req and schedules it for analysis,
+// returning immediately with the job's id.
+func (q *JobQueue) Enqueue(req JobRequest) *Job {
+	job := &Job{
+		ID:        fmt.Sprintf("job-%d", time.Now().UnixNano()),
+		Status:    JobQueued,
+		Request:   req,
+		CreatedAt: time.Now(),
+		cancel:    make(chan struct{}),
+	}
+
+	q.mu.Lock()
+	q.jobs[job.ID] = job
+	q.mu.Unlock()
+
+	q.persist(job)
+	q.pending <- job
+
+	return job
+}
+
+// Get returns a previously enqueued job by id.
+func (q *JobQueue) Get(id string) (*Job, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	job, ok := q.jobs[id]
+	return job, ok
+}
+
+// Snapshot returns a point-in-time copy of job's externally visible state,
+// safe to JSON-encode or iterate from another goroutine.
+func (q *JobQueue) Snapshot(job *Job) JobView {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return JobView{
+		ID:        job.ID,
+		Status:    job.Status,
+		Request:   job.Request,
+		Result:    job.Result,
+		Results:   job.Results,
+		Error:     job.Error,
+		Events:    append([]JobEvent(nil), job.Events...),
+		CreatedAt: job.CreatedAt,
+	}
+}
+
+// Subscribe registers a new SSE subscriber for job, returning every event
+// already recorded plus a channel that receives events emitted afterward.
+// If job has already reached a terminal status, the returned channel is
+// closed immediately since no further events are coming. Callers must
+// invoke the returned cancel func once they stop reading (e.g. the client
+// disconnects) so the subscriber's channel is released.
+func (q *JobQueue) Subscribe(job *Job) ([]JobEvent, <-chan JobEvent, func()) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	events := append([]JobEvent(nil), job.Events...)
+	ch := make(chan JobEvent, 16)
+
+	if isTerminal(job.Status) {
+		close(ch)
+		return events, ch, func() {}
+	}
+
+	job.subscribers = append(job.subscribers, ch)
+
+	cancel := func() {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		for i, c := range job.subscribers {
+			if c == ch {
+				job.subscribers = append(job.subscribers[:i], job.subscribers[i+1:]...)
+				break
+			}
+		}
+	}
+
+	return events, ch, cancel
+}
+
+// Cancel marks a queued or running job as cancelled.
+func (q *JobQueue) Cancel(id string) bool {
+	q.mu.Lock()
+	job, ok := q.jobs[id]
+	q.mu.Unlock()
+	if !ok {
+		return false
+	}
+
+	job.cancelOnce.Do(func() { close(job.cancel) })
+
+	return true
+}
+
+func (q *JobQueue) worker() {
+	defer q.wg.Done()
+
+	for {
+		select {
+		case <-q.stop:
+			return
+		case job := <-q.pending:
+			q.run(job)
+		}
+	}
+}
+
+func (q *JobQueue) run(job *Job) {
+	select {
+	case <-job.cancel:
+		q.finish(job, JobCancelled, nil, nil, nil)
+		return
+	default:
+	}
+
+	q.mu.Lock()
+	job.Status = JobRunning
+	q.mu.Unlock()
+	q.persist(job)
+
+	if job.Request.Multi {
+		results, err := analyzeMulti(
+			false,
+			job.Request.Path,
+			"",
+			job.Request.Environment,
+			true,
+			job.Request.Overwrite,
+			job.Request.Generator,
+			job.Request.UseRegistry)
+		if err != nil {
+			q.finish(job, JobFailed, nil, nil, err)
+			return
+		}
+		q.finish(job, JobDone, nil, results, nil)
+		return
+	}
+
+	progress := func(step, message string) {
+		q.emit(job, step, message)
+	}
+	cancelled := func() bool {
+		select {
+		case <-job.cancel:
+			return true
+		default:
+			return false
+		}
+	}
+
+	result, err := analyze(
+		false,
+		job.Request.Path,
+		"",
+		job.Request.Environment,
+		true,
+		job.Request.Overwrite,
+		job.Request.Generator,
+		"",
+		"",
+		job.Request.UseRegistry,
+		progress,
+		cancelled)
+
+	if err != nil {
+		if errors.Is(err, ErrCancelled) {
+			q.finish(job, JobCancelled, nil, nil, nil)
+			return
+		}
+		q.finish(job, JobFailed, nil, nil, err)
+		return
+	}
+
+	q.finish(job, JobDone, result, nil, nil)
+}
+
+func (q *JobQueue) emit(job *Job, step, message string) {
+	event := JobEvent{Step: step, Message: message, At: time.Now()}
+
+	q.mu.Lock()
+	job.Events = append(job.Events, event)
+	for _, ch := range job.subscribers {
+		select {
+		case ch <- event:
+		default:
+			// a slow subscriber shouldn't block the worker
+		}
+	}
+	q.mu.Unlock()
+
+	q.persist(job)
+}
+
+func (q *JobQueue) finish(job *Job, status JobStatus, result *analysisResult, results []*analysisResult, err error) {
+	q.mu.Lock()
+	job.Status = status
+	job.Result = result
+	job.Results = results
+	if err != nil {
+		job.Error = err.Error()
+	}
+	subscribers := job.subscribers
+	job.subscribers = nil
+	q.mu.Unlock()
+
+	q.persist(job)
+	for _, ch := range subscribers {
+		close(ch)
+	}
+
+	common.PrintlnL1("Job %s finished with status %s", job.ID, status)
+}
+
+func (q *JobQueue) persist(job *Job) {
+	q.mu.Lock()
+	data, err := json.Marshal(job)
+	q.mu.Unlock()
+	if err != nil {
+		return
+	}
+
+	path := filepath.Join(q.workspaceDir, "jobs", job.ID+".json")
+	_ = ioutil.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCancelIsIdempotent(t *testing.T) {
+	q, err := NewJobQueue(t.TempDir(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	job := q.Enqueue(JobRequest{Path: "/tmp/does-not-matter"})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			q.Cancel(job.ID)
+		}()
+	}
+	wg.Wait()
+
+	select {
+	case <-job.cancel:
+	default:
+		t.Fatal("expected job.cancel to be closed after concurrent Cancel calls")
+	}
+}
+
+func TestCancelUnknownJobReturnsFalse(t *testing.T) {
+	q, err := NewJobQueue(t.TempDir(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if q.Cancel("no-such-job") {
+		t.Fatal("expected Cancel of an unknown job id to return false")
+	}
+}
+
+func TestSubscribeReplaysThenFansOutToEverySubscriber(t *testing.T) {
+	q, err := NewJobQueue(t.TempDir(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	job := q.Enqueue(JobRequest{})
+	q.emit(job, "detect", "starting detect")
+
+	events1, ch1, cancel1 := q.Subscribe(job)
+	defer cancel1()
+	events2, ch2, cancel2 := q.Subscribe(job)
+	defer cancel2()
+
+	if len(events1) != 1 || len(events2) != 1 {
+		t.Fatalf("got %d/%d replayed events, want 1/1", len(events1), len(events2))
+	}
+
+	q.emit(job, "write-dockerfile", "starting write-dockerfile")
+
+	for _, ch := range []<-chan JobEvent{ch1, ch2} {
+		select {
+		case event := <-ch:
+			if event.Step != "write-dockerfile" {
+				t.Fatalf("got step %q, want write-dockerfile", event.Step)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for fanned-out event")
+		}
+	}
+
+	q.finish(job, JobDone, &analysisResult{Ok: true}, nil, nil)
+
+	for _, ch := range []<-chan JobEvent{ch1, ch2} {
+		if _, ok := <-ch; ok {
+			t.Fatal("expected subscriber channel to be closed once the job finished")
+		}
+	}
+}
+
+func TestSubscribeAfterFinishReturnsAClosedChannel(t *testing.T) {
+	q, err := NewJobQueue(t.TempDir(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	job := q.Enqueue(JobRequest{})
+	q.emit(job, "detect", "starting detect")
+	q.finish(job, JobDone, &analysisResult{Ok: true}, nil, nil)
+
+	events, ch, cancel := q.Subscribe(job)
+	defer cancel()
+
+	if len(events) != 1 {
+		t.Fatalf("got %d replayed events, want 1", len(events))
+	}
+	if _, ok := <-ch; ok {
+		t.Fatal("expected a subscriber joining after finish to get an already-closed channel")
+	}
+}
+
+func TestSnapshotDoesNotRaceWithConcurrentStatusWrites(t *testing.T) {
+	q, err := NewJobQueue(t.TempDir(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	job := q.Enqueue(JobRequest{})
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			q.emit(job, "detect", "tick")
+		}
+		q.finish(job, JobDone, &analysisResult{Ok: true}, nil, nil)
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			_ = q.Snapshot(job)
+		}
+	}()
+	wg.Wait()
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloud66-oss/starter/packs"
+	"github.com/cloud66-oss/starter/packs/kube"
+)
+
+// writeAndMaybeDeployKube renders the full Kubernetes manifest set for pack
+// under path/kubernetes (or a Helm chart under path/chart when asHelm is
+// true), and applies it to the current kube-context when --deploy was set.
+func writeAndMaybeDeployKube(pack packs.Pack, path string, asHelm bool) error {
+	imageRepo, imageTag := splitImageFlag(flagImage)
+
+	set, err := kube.Generate(pack, kube.Options{
+		Namespace:       flagNamespace,
+		ImageRepository: imageRepo,
+		ImageTag:        imageTag,
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to generate kubernetes manifests due to: %s", err.Error())
+	}
+
+	var outDir string
+	if asHelm {
+		outDir = filepath.Join(path, "chart")
+		err = kube.WriteHelmChart(outDir, pack.Name(), kube.ChartOptions{
+			ImageRepository: imageRepo,
+			ImageTag:        imageTag,
+			HasPVC:          set.PersistentVolumeClaim != "",
+		}, flagOverwrite)
+	} else {
+		outDir = filepath.Join(path, "kubernetes")
+		err = kube.Write(outDir, set, flagOverwrite)
+	}
+	if err != nil {
+		return fmt.Errorf("Failed to write kubernetes manifests due to: %s", err.Error())
+	}
+
+	if !flagDeploy {
+		return nil
+	}
+
+	deployOpts := kube.DeployOptions{Namespace: flagNamespace, DryRun: flagDryRun}
+	if asHelm {
+		err = kube.DeployChart(outDir, pack.Name(), deployOpts)
+	} else {
+		err = kube.Deploy(outDir, deployOpts)
+	}
+	if err != nil {
+		return fmt.Errorf("Failed to deploy generated manifests due to: %s", err.Error())
+	}
+
+	return nil
+}
+
+// splitImageFlag splits a "--image repo:tag" value into its repository and
+// tag parts, leaving both empty (so kube.Generate falls back to its own
+// defaults) when no --image was given.
+func splitImageFlag(image string) (string, string) {
+	if image == "" {
+		return "", ""
+	}
+
+	idx := strings.LastIndex(image, ":")
+	if idx == -1 {
+		return image, ""
+	}
+	return image[:idx], image[idx+1:]
+}
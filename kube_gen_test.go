@@ -0,0 +1,24 @@
+package main
+
+import "testing"
+
+func TestSplitImageFlag(t *testing.T) {
+	cases := []struct {
+		image    string
+		wantRepo string
+		wantTag  string
+	}{
+		{"", "", ""},
+		{"myapp", "myapp", ""},
+		{"myapp:latest", "myapp", "latest"},
+		{"registry.example.com:5000/myapp:v1.2.3", "registry.example.com:5000/myapp", "v1.2.3"},
+	}
+
+	for _, c := range cases {
+		repo, tag := splitImageFlag(c.image)
+		if repo != c.wantRepo || tag != c.wantTag {
+			t.Errorf("splitImageFlag(%q) = (%q, %q), want (%q, %q)",
+				c.image, repo, tag, c.wantRepo, c.wantTag)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cloud66-oss/starter/common"
+	"github.com/cloud66-oss/starter/packs"
+)
+
+// DetectedComponent ties a detected pack to the subdirectory it was found in,
+// so a monorepo analysis can keep each component's generated files separate
+// while still producing one combined manifest.
+type DetectedComponent struct {
+	Path string
+	Pack packs.Pack
+}
+
+// DetectComponents walks the immediate subdirectories of path looking for a
+// component per directory, in addition to path itself. It mirrors Detect but
+// is aimed at monorepos where a single framework per path doesn't hold.
+func DetectComponents(path string, noPrompt bool) ([]DetectedComponent, error) {
+	var components []DetectedComponent
+
+	candidates := []string{path}
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() || isIgnoredComponentDir(entry.Name()) {
+			continue
+		}
+		candidates = append(candidates, filepath.Join(path, entry.Name()))
+	}
+
+	for _, candidate := range candidates {
+		detectedPacks, err := Detect(candidate)
+		if err != nil || len(detectedPacks) == 0 {
+			continue
+		}
+
+		pack, err := choosePack(detectedPacks, noPrompt)
+		if err != nil || pack == nil {
+			continue
+		}
+
+		components = append(components, DetectedComponent{Path: candidate, Pack: pack})
+	}
+
+	if len(components) == 0 {
+		return nil, fmt.Errorf("Failed to detect any components under %s\n", path)
+	}
+
+	return components, nil
+}
+
+func isIgnoredComponentDir(name string) bool {
+	switch name {
+	case ".git", "vendor", "node_modules":
+		return true
+	default:
+		return false
+	}
+}
+
+// analyzeMulti runs analyze() per detected component of a monorepo and emits
+// a top-level service.yml that wires all of the components together, in the
+// spirit of OpenShift's new-app command coordinating several BuildConfigs
+// from multiple SOURCE/IMAGE/URL arguments.
+func analyzeMulti(
+	updateTemplates bool,
+	path string,
+	templates string,
+	environment string,
+	noPrompt bool,
+	overwrite bool,
+	generator string,
+	useRegistry bool,
+) ([]*analysisResult, error) {
+
+	if path == "" {
+		pwd, err := os.Getwd()
+		if err != nil {
+			return nil, fmt.Errorf("Unable to detect current directory path due to %s", err.Error())
+		}
+		path = pwd
+	}
+
+	common.PrintlnTitle("Detecting components for the monorepo at %s", path)
+
+	components, err := DetectComponents(path, noPrompt)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []*analysisResult
+	for _, component := range components {
+		common.PrintlnL0("Analyzing component %s (%s)", component.Path, component.Pack.Name())
+
+		result, err := analyze(
+			updateTemplates,
+			component.Path,
+			templates,
+			environment,
+			noPrompt,
+			overwrite,
+			generator,
+			"",
+			"",
+			useRegistry,
+			nil,
+			nil)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to analyze component at %s due to: %s", component.Path, err.Error())
+		}
+
+		results = append(results, result)
+	}
+
+	if err := writeCombinedManifest(path, components, overwrite); err != nil {
+		return nil, fmt.Errorf("Failed to write combined service.yml due to: %s", err.Error())
+	}
+
+	return results, nil
+}
+
+// writeCombinedManifest emits a single top-level service.yml that references
+// each component's own service.yml by its relative path, so the whole
+// monorepo can be deployed as one coordinated stack.
+func writeCombinedManifest(path string, components []DetectedComponent, overwrite bool) error {
+	manifestPath := filepath.Join(path, "service.yml")
+	if _, err := os.Stat(manifestPath); err == nil && !overwrite {
+		return fmt.Errorf("service.yml already exists. Use overwrite flag to overwrite it")
+	}
+
+	file, err := os.Create(manifestPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	fmt.Fprintln(file, "services:")
+	for _, component := range components {
+		relPath, err := filepath.Rel(path, component.Path)
+		if err != nil {
+			relPath = component.Path
+		}
+		if relPath == "." {
+			relPath = ""
+		}
+
+		name := component.Pack.Name()
+		if relPath != "" {
+			name = filepath.Base(relPath)
+		}
+
+		fmt.Fprintf(file, "  %s:\n", name)
+		fmt.Fprintf(file, "    build: %s\n", relPath)
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package kube
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+// DeployOptions controls how generated manifests or a chart are applied to
+// the current kube-context. There's no overwrite protection here: deploying
+// is applying already-written manifests, not writing new files, so it has
+// nothing to overwrite.
+type DeployOptions struct {
+	Namespace string
+	DryRun    bool
+}
+
+// Deployer applies generated manifests or a Helm chart to a kube-context.
+// execDeployer (below) is the only implementation today, shelling out to
+// the kubectl/helm binaries on PATH rather than calling client-go/the Helm
+// SDK in-process — a deliberate scope cut (the CLI binaries give the same
+// apply/upgrade --install semantics with far less code, at the cost of
+// requiring them on PATH and losing typed Go errors). Deployer exists so a
+// client-go/Helm-SDK-backed implementation can be swapped in behind
+// DefaultDeployer later without touching callers.
+type Deployer interface {
+	Deploy(dir string, opts DeployOptions) error
+	DeployChart(dir, releaseName string, opts DeployOptions) error
+}
+
+// execDeployer implements Deployer by shelling out to the kubectl and helm
+// binaries on PATH.
+type execDeployer struct{}
+
+// DefaultDeployer is the Deployer used unless a caller wires up another one.
+var DefaultDeployer Deployer = execDeployer{}
+
+// Deploy applies the manifests at dir to the current kube-context using
+// DefaultDeployer, respecting namespace and dry-run.
+func Deploy(dir string, opts DeployOptions) error {
+	return DefaultDeployer.Deploy(dir, opts)
+}
+
+// DeployChart installs or upgrades the Helm chart at dir into the current
+// kube-context using DefaultDeployer, respecting namespace and dry-run.
+func DeployChart(dir, releaseName string, opts DeployOptions) error {
+	return DefaultDeployer.DeployChart(dir, releaseName, opts)
+}
+
+func (execDeployer) Deploy(dir string, opts DeployOptions) error {
+	args := []string{"apply", "-f", dir}
+	if opts.Namespace != "" {
+		args = append(args, "-n", opts.Namespace)
+	}
+	if opts.DryRun {
+		args = append(args, "--dry-run=client")
+	}
+
+	return runKubeCommand("kubectl", args)
+}
+
+func (execDeployer) DeployChart(dir, releaseName string, opts DeployOptions) error {
+	args := []string{"upgrade", "--install", releaseName, dir}
+	if opts.Namespace != "" {
+		args = append(args, "--namespace", opts.Namespace, "--create-namespace")
+	}
+	if opts.DryRun {
+		args = append(args, "--dry-run")
+	}
+
+	return runKubeCommand("helm", args)
+}
+
+func runKubeCommand(name string, args []string) error {
+	cmd := exec.Command(name, args...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s %s failed: %s: %s", name, args, err.Error(), string(output))
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package kube
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// ChartOptions configures the Helm chart's default values.yaml. The
+// templates themselves always read from .Values, so these are just the
+// starting point a `helm install` without -f/--set would use.
+type ChartOptions struct {
+	ImageRepository string
+	ImageTag        string
+	HasPVC          bool
+}
+
+// WriteHelmChart lays out a minimal Helm chart under dir: Chart.yaml,
+// values.yaml and Go-template manifests under templates/ that actually
+// interpolate .Values, so editing values.yaml (or `helm install --set ...`)
+// changes what gets deployed.
+func WriteHelmChart(dir, chartName string, opts ChartOptions, overwrite bool) error {
+	templatesDir := filepath.Join(dir, "templates")
+	if err := os.MkdirAll(templatesDir, 0755); err != nil {
+		return err
+	}
+
+	imageRepo := opts.ImageRepository
+	if imageRepo == "" {
+		imageRepo = chartName
+	}
+	imageTag := opts.ImageTag
+	if imageTag == "" {
+		imageTag = "latest"
+	}
+
+	chartYAML := fmt.Sprintf(`apiVersion: v2
+name: %s
+description: A Helm chart for %s, generated by Starter
+type: application
+version: 0.1.0
+appVersion: "1.0"
+`, chartName, chartName)
+
+	valuesYAML := fmt.Sprintf(`replicaCount: 1
+image:
+  repository: %s
+  tag: "%s"
+service:
+  port: 80
+  targetPort: 8080
+`, imageRepo, imageTag)
+
+	if err := writeChartFile(filepath.Join(dir, "Chart.yaml"), chartYAML, overwrite); err != nil {
+		return err
+	}
+	if err := writeChartFile(filepath.Join(dir, "values.yaml"), valuesYAML, overwrite); err != nil {
+		return err
+	}
+
+	templates := map[string]string{
+		"deployment.yaml": helmDeploymentTemplate(opts.HasPVC),
+		"service.yaml":    helmServiceTemplate(),
+		"ingress.yaml":    helmIngressTemplate(),
+		"configmap.yaml":  helmConfigMapTemplate(),
+	}
+	if opts.HasPVC {
+		templates["persistentvolumeclaim.yaml"] = helmPVCTemplate()
+	}
+
+	for name, content := range templates {
+		if err := writeChartFile(filepath.Join(templatesDir, name), content, overwrite); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeChartFile(path, content string, overwrite bool) error {
+	if _, err := os.Stat(path); err == nil && !overwrite {
+		return fmt.Errorf("%s already exists. Use overwrite flag to overwrite it", path)
+	}
+	return ioutil.WriteFile(path, []byte(content), 0644)
+}
+
+func helmDeploymentTemplate(hasPVC bool) string {
+	volumeMounts := ""
+	volumes := ""
+	if hasPVC {
+		volumeMounts = `
+          volumeMounts:
+            - name: data
+              mountPath: /data`
+		volumes = `
+      volumes:
+        - name: data
+          persistentVolumeClaim:
+            claimName: {{ .Release.Name }}-data`
+	}
+
+	return fmt.Sprintf(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ .Release.Name }}
+  namespace: {{ .Release.Namespace }}
+spec:
+  replicas: {{ .Values.replicaCount }}
+  selector:
+    matchLabels:
+      app: {{ .Release.Name }}
+  template:
+    metadata:
+      labels:
+        app: {{ .Release.Name }}
+    spec:
+      containers:
+        - name: {{ .Release.Name }}
+          image: "{{ .Values.image.repository }}:{{ .Values.image.tag }}"
+          ports:
+            - containerPort: {{ .Values.service.targetPort }}%s%s
+`, volumeMounts, volumes)
+}
+
+func helmServiceTemplate() string {
+	return `apiVersion: v1
+kind: Service
+metadata:
+  name: {{ .Release.Name }}
+  namespace: {{ .Release.Namespace }}
+spec:
+  selector:
+    app: {{ .Release.Name }}
+  ports:
+    - port: {{ .Values.service.port }}
+      targetPort: {{ .Values.service.targetPort }}
+`
+}
+
+func helmIngressTemplate() string {
+	return `apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: {{ .Release.Name }}
+  namespace: {{ .Release.Namespace }}
+spec:
+  rules:
+    - http:
+        paths:
+          - path: /
+            pathType: Prefix
+            backend:
+              service:
+                name: {{ .Release.Name }}
+                port:
+                  number: {{ .Values.service.port }}
+`
+}
+
+func helmConfigMapTemplate() string {
+	return `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Release.Name }}-config
+  namespace: {{ .Release.Namespace }}
+data:
+  LANGUAGE: {{ .Chart.Name }}
+`
+}
+
+func helmPVCTemplate() string {
+	return `apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: {{ .Release.Name }}-data
+  namespace: {{ .Release.Namespace }}
+spec:
+  accessModes:
+    - ReadWriteOnce
+  resources:
+    requests:
+      storage: 1Gi
+`
+}
@@ -0,0 +1,223 @@
+// Package kube generates native Kubernetes manifests (and an optional Helm
+// chart) for a detected pack, growing the old -g kube path into a real
+// subsystem of its own rather than a single service.yml conversion.
+package kube
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloud66-oss/starter/packs"
+)
+
+// ManifestSet holds the rendered YAML for every resource generated for a
+// pack, keyed by file name so callers can write them individually or feed
+// them into a Helm chart's templates/ directory.
+type ManifestSet struct {
+	Deployment            string
+	Service               string
+	Ingress               string
+	ConfigMap             string
+	PersistentVolumeClaim string
+}
+
+// Options controls how the manifests are rendered. Overwrite protection
+// itself is enforced where the manifests are actually written (Write,
+// WriteHelmChart), not here.
+type Options struct {
+	Namespace string
+
+	// ImageRepository/ImageTag identify the application's own built image,
+	// e.g. the one pushed by the project's CI. They default to the
+	// detected pack's name and "latest" — NOT the language runtime version,
+	// which is a build-time base image rather than something you can run.
+	ImageRepository string
+	ImageTag        string
+}
+
+// Generate builds a ManifestSet for pack, including a PersistentVolumeClaim
+// when the pack reports a database that needs persistent storage.
+func Generate(pack packs.Pack, opts Options) (*ManifestSet, error) {
+	namespace := opts.Namespace
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	name := sanitizeName(pack.Name())
+
+	image := opts.ImageRepository
+	if image == "" {
+		image = name
+	}
+	tag := opts.ImageTag
+	if tag == "" {
+		tag = "latest"
+	}
+
+	hasPVC := needsPersistentStorage(pack)
+
+	set := &ManifestSet{
+		Deployment: renderDeployment(name, namespace, image, tag, hasPVC),
+		Service:    renderService(name, namespace),
+		Ingress:    renderIngress(name, namespace),
+		ConfigMap:  renderConfigMap(name, namespace, pack),
+	}
+
+	if hasPVC {
+		set.PersistentVolumeClaim = renderPVC(name, namespace)
+	}
+
+	return set, nil
+}
+
+// Write persists every non-empty manifest in set as its own YAML file under
+// dir, named after the resource kind (e.g. deployment.yml).
+func Write(dir string, set *ManifestSet, overwrite bool) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	files := map[string]string{
+		"deployment.yml":            set.Deployment,
+		"service.yml":               set.Service,
+		"ingress.yml":               set.Ingress,
+		"configmap.yml":             set.ConfigMap,
+		"persistentvolumeclaim.yml": set.PersistentVolumeClaim,
+	}
+
+	for name, content := range files {
+		if content == "" {
+			continue
+		}
+
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil && !overwrite {
+			return fmt.Errorf("%s already exists. Use overwrite flag to overwrite it", path)
+		}
+
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func needsPersistentStorage(pack packs.Pack) bool {
+	for _, db := range pack.GetDatabases() {
+		switch strings.ToLower(db) {
+		case "postgresql", "mysql", "mongodb", "redis":
+			return true
+		}
+	}
+	return false
+}
+
+func sanitizeName(name string) string {
+	name = strings.ToLower(name)
+	name = strings.ReplaceAll(name, "_", "-")
+	name = strings.ReplaceAll(name, ".", "-")
+	return name
+}
+
+func renderDeployment(name, namespace, image, tag string, hasPVC bool) string {
+	volumes := ""
+	volumeMounts := ""
+	if hasPVC {
+		volumeMounts = fmt.Sprintf(`
+          volumeMounts:
+            - name: data
+              mountPath: /data`)
+		volumes = fmt.Sprintf(`
+      volumes:
+        - name: data
+          persistentVolumeClaim:
+            claimName: %s-data`, name)
+	}
+
+	return fmt.Sprintf(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: %s
+  namespace: %s
+spec:
+  replicas: 1
+  selector:
+    matchLabels:
+      app: %s
+  template:
+    metadata:
+      labels:
+        app: %s
+    spec:
+      containers:
+        - name: %s
+          image: %s:%s
+          ports:
+            - containerPort: 8080%s%s
+`, name, namespace, name, name, name, image, tag, volumeMounts, volumes)
+}
+
+func renderService(name, namespace string) string {
+	return fmt.Sprintf(`apiVersion: v1
+kind: Service
+metadata:
+  name: %s
+  namespace: %s
+spec:
+  selector:
+    app: %s
+  ports:
+    - port: 80
+      targetPort: 8080
+`, name, namespace, name)
+}
+
+func renderIngress(name, namespace string) string {
+	return fmt.Sprintf(`apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: %s
+  namespace: %s
+spec:
+  rules:
+    - http:
+        paths:
+          - path: /
+            pathType: Prefix
+            backend:
+              service:
+                name: %s
+                port:
+                  number: 80
+`, name, namespace, name)
+}
+
+func renderConfigMap(name, namespace string, pack packs.Pack) string {
+	return fmt.Sprintf(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: %s-config
+  namespace: %s
+data:
+  LANGUAGE: %s
+`, name, namespace, pack.Name())
+}
+
+func renderPVC(name, namespace string) string {
+	return fmt.Sprintf(`apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: %s-data
+  namespace: %s
+spec:
+  accessModes:
+    - ReadWriteOnce
+  resources:
+    requests:
+      storage: 1Gi
+`, name, namespace)
+}
@@ -0,0 +1,18 @@
+package kube
+
+import "testing"
+
+func TestSanitizeName(t *testing.T) {
+	cases := map[string]string{
+		"Rails_App":      "rails-app",
+		"my.service.app": "my-service-app",
+		"already-ok":     "already-ok",
+		"Mixed_Case.App": "mixed-case-app",
+	}
+
+	for in, want := range cases {
+		if got := sanitizeName(in); got != want {
+			t.Errorf("sanitizeName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package packs
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/heroku/docker-registry-client/registry"
+)
+
+// TagInfo describes a single tag resolved from a registry, along with
+// enough metadata to let callers filter by platform/arch and pick a
+// reproducible base image (digest, size, creation time).
+type TagInfo struct {
+	Tag      string
+	Digest   string
+	Platform string
+	Size     int64
+	Created  time.Time
+}
+
+// RegistryClient abstracts away the concrete registry backend (Docker Hub,
+// GHCR, Quay, ECR, GCR or a generic OCI Distribution v2 endpoint) so
+// analyze() doesn't need to hard-code registry-1.docker.io.
+type RegistryClient interface {
+	// Tags returns every tag found for repo, along with its digest and
+	// platform when the registry exposes that information.
+	Tags(repo string) ([]TagInfo, error)
+
+	// Resolve pulls the manifest for repo:tag and confirms it actually
+	// exists for the requested platform, filling in Digest, Size and
+	// Created. It returns an error if the tag isn't available.
+	Resolve(repo, tag string) (TagInfo, error)
+}
+
+// RegistryOptions configures how a RegistryClient talks to its backend.
+type RegistryOptions struct {
+	URL      string
+	Username string
+	Password string
+	Platform string
+}
+
+// NewRegistryClient picks a RegistryClient implementation based on opts.URL,
+// defaulting to Docker Hub when no URL is given.
+func NewRegistryClient(opts RegistryOptions) (RegistryClient, error) {
+	url := opts.URL
+	if url == "" {
+		url = "https://registry-1.docker.io/"
+	}
+
+	username, password := opts.Username, opts.Password
+
+	switch {
+	case strings.Contains(url, "amazonaws.com") && password == "":
+		// ECR doesn't take long-lived passwords: ask the aws CLI for a
+		// token good for 12 hours, the same thing `docker login` does.
+		token, err := ecrLoginPassword()
+		if err != nil {
+			return nil, fmt.Errorf("can't get an ECR login token, pass --registry-password or run 'aws ecr get-login-password' yourself: %s", err.Error())
+		}
+		username, password = "AWS", token
+	case strings.Contains(url, "gcr.io") && password == "":
+		// GCR accepts a short-lived gcloud access token as the password.
+		token, err := gcloudAccessToken()
+		if err != nil {
+			return nil, fmt.Errorf("can't get a GCR access token, pass --registry-password or run 'gcloud auth print-access-token' yourself: %s", err.Error())
+		}
+		username, password = "oauth2accesstoken", token
+	}
+
+	hub, err := registry.New(url, username, password)
+	if err != nil {
+		return nil, fmt.Errorf("can't connect to registry %s: %s", url, err.Error())
+	}
+
+	client := &genericRegistryClient{hub: hub, platform: opts.Platform}
+
+	switch {
+	case strings.Contains(url, "docker.io"):
+		return &dockerHubClient{genericRegistryClient: client}, nil
+	case strings.Contains(url, "ghcr.io"):
+		return &ghcrClient{genericRegistryClient: client}, nil
+	case strings.Contains(url, "quay.io"):
+		return &quayClient{genericRegistryClient: client}, nil
+	case strings.Contains(url, "amazonaws.com"):
+		return &ecrClient{genericRegistryClient: client}, nil
+	case strings.Contains(url, "gcr.io"):
+		return &gcrClient{genericRegistryClient: client}, nil
+	default:
+		return client, nil
+	}
+}
+
+// ecrLoginPassword shells out to the aws CLI the same way `docker login`
+// would, since ECR has no long-lived password to put in ~/.docker/config.json.
+func ecrLoginPassword() (string, error) {
+	out, err := exec.Command("aws", "ecr", "get-login-password").Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// gcloudAccessToken shells out to the gcloud CLI to get a short-lived token
+// for GCR, mirroring how `docker login` against gcr.io is normally done.
+func gcloudAccessToken() (string, error) {
+	out, err := exec.Command("gcloud", "auth", "print-access-token").Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// genericRegistryClient implements RegistryClient against any registry that
+// speaks the OCI Distribution v2 API, which covers all of the
+// backend-specific clients below as well.
+type genericRegistryClient struct {
+	hub      *registry.Registry
+	platform string
+}
+
+func (c *genericRegistryClient) Tags(repo string) ([]TagInfo, error) {
+	tags, err := c.hub.Tags(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	// Platform here is just the filter the caller asked for: listing tags
+	// is one cheap call per repo and doesn't pull every manifest to check
+	// it, so it's unverified until Resolve confirms a specific candidate.
+	infos := make([]TagInfo, 0, len(tags))
+	for _, tag := range tags {
+		infos = append(infos, TagInfo{Tag: tag, Platform: c.platform})
+	}
+
+	return infos, nil
+}
+
+// imageConfig is the subset of the OCI/Docker image config blob needed to
+// tell which platform a manifest was actually built for.
+type imageConfig struct {
+	OS           string `json:"os"`
+	Architecture string `json:"architecture"`
+}
+
+// Resolve confirms that repo:tag exists for c.platform by pulling its
+// manifest and, when a platform was requested, its image config blob, so a
+// candidate tag that only matched the release mask by name doesn't get
+// offered to the user as a dead end for an architecture it was never built
+// for.
+func (c *genericRegistryClient) Resolve(repo, tag string) (TagInfo, error) {
+	manifest, err := c.hub.ManifestV2(repo, tag)
+	if err != nil {
+		return TagInfo{}, fmt.Errorf("tag %s not found for %s: %s", tag, repo, err.Error())
+	}
+
+	digest := ""
+	if d, err := c.hub.ManifestDigest(repo, tag); err == nil {
+		digest = d.String()
+	}
+
+	resolvedPlatform, err := c.resolvePlatform(repo, manifest.Config.Digest.String())
+	if err != nil {
+		return TagInfo{}, fmt.Errorf("can't read image config for %s:%s: %s", repo, tag, err.Error())
+	}
+
+	if c.platform != "" && resolvedPlatform != "" && resolvedPlatform != c.platform {
+		return TagInfo{}, fmt.Errorf("%s:%s is built for %s, not requested platform %s", repo, tag, resolvedPlatform, c.platform)
+	}
+
+	return TagInfo{
+		Tag:      tag,
+		Digest:   digest,
+		Platform: resolvedPlatform,
+		Size:     manifest.Config.Size,
+	}, nil
+}
+
+// resolvePlatform downloads the image config blob at configDigest and
+// reports it as "os/architecture", the same shape as --platform.
+func (c *genericRegistryClient) resolvePlatform(repo, configDigest string) (string, error) {
+	blob, err := c.hub.DownloadBlob(repo, configDigest)
+	if err != nil {
+		return "", err
+	}
+	defer blob.Close()
+
+	data, err := ioutil.ReadAll(blob)
+	if err != nil {
+		return "", err
+	}
+
+	var config imageConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return "", err
+	}
+	if config.OS == "" || config.Architecture == "" {
+		return "", nil
+	}
+
+	return config.OS + "/" + config.Architecture, nil
+}
+
+// dockerHubClient resolves "<image>" tags as "library/<image>" the way
+// Docker Hub's official images are namespaced.
+type dockerHubClient struct {
+	*genericRegistryClient
+}
+
+func (c *dockerHubClient) Tags(repo string) ([]TagInfo, error) {
+	return c.genericRegistryClient.Tags(dockerHubRepo(repo))
+}
+
+func (c *dockerHubClient) Resolve(repo, tag string) (TagInfo, error) {
+	return c.genericRegistryClient.Resolve(dockerHubRepo(repo), tag)
+}
+
+// dockerHubRepo applies Docker Hub's "library/<image>" namespacing to an
+// unqualified official-image repo, e.g. "node" -> "library/node".
+func dockerHubRepo(repo string) string {
+	if !strings.Contains(repo, "/") {
+		return "library/" + repo
+	}
+	return repo
+}
+
+// ghcrClient talks to ghcr.io, which namespaces images under the owning
+// GitHub org/user, e.g. ghcr.io/<owner>/<image>.
+type ghcrClient struct {
+	*genericRegistryClient
+}
+
+// quayClient talks to quay.io, namespaced as quay.io/<org>/<image>.
+type quayClient struct {
+	*genericRegistryClient
+}
+
+// ecrClient talks to an AWS Elastic Container Registry endpoint.
+type ecrClient struct {
+	*genericRegistryClient
+}
+
+// gcrClient talks to a Google Container Registry endpoint.
+type gcrClient struct {
+	*genericRegistryClient
+}
@@ -0,0 +1,114 @@
+package packs
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// cachedTags is the on-disk shape of a registry-cache entry.
+type cachedTags struct {
+	FetchedAt time.Time `json:"fetched_at"`
+	Tags      []TagInfo `json:"tags"`
+}
+
+// CachingRegistryClient wraps a RegistryClient with a small on-disk cache
+// under cacheDir (typically ~/.starter/registry-cache), keyed by
+// registry@repo@tag@platform, so repeated runs against the same project
+// don't re-hit the registry within ttl, while switching --registry-url or
+// --platform between runs still bypasses a cache entry from a different
+// backend/platform instead of reusing it.
+type CachingRegistryClient struct {
+	client      RegistryClient
+	cacheDir    string
+	ttl         time.Duration
+	registryURL string
+	platform    string
+}
+
+// NewCachingRegistryClient wraps client with a cache rooted at cacheDir.
+// registryURL and platform identify the backend/filter client was built
+// with, so they can be folded into the cache key.
+func NewCachingRegistryClient(client RegistryClient, cacheDir string, ttl time.Duration, registryURL, platform string) *CachingRegistryClient {
+	return &CachingRegistryClient{client: client, cacheDir: cacheDir, ttl: ttl, registryURL: registryURL, platform: platform}
+}
+
+// Tags is cached under the repo's own key, since it covers every tag in
+// one round-trip rather than a single repo@tag.
+func (c *CachingRegistryClient) Tags(repo string) ([]TagInfo, error) {
+	cachePath := c.cachePathFor(repo)
+
+	if cached, ok := c.readCache(cachePath); ok {
+		return cached.Tags, nil
+	}
+
+	tags, err := c.client.Tags(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	c.writeCache(cachePath, tags)
+
+	return tags, nil
+}
+
+// Resolve is cached under repo@tag, since it's the expensive manifest pull
+// that runs once per candidate on every invocation.
+func (c *CachingRegistryClient) Resolve(repo, tag string) (TagInfo, error) {
+	cachePath := c.cachePathFor(repo + "@" + tag)
+
+	if cached, ok := c.readCache(cachePath); ok && len(cached.Tags) == 1 {
+		return cached.Tags[0], nil
+	}
+
+	info, err := c.client.Resolve(repo, tag)
+	if err != nil {
+		return TagInfo{}, err
+	}
+
+	c.writeCache(cachePath, []TagInfo{info})
+
+	return info, nil
+}
+
+func (c *CachingRegistryClient) cachePathFor(key string) string {
+	key = c.registryURL + "@" + key + "@" + c.platform
+	key = strings.ReplaceAll(key, "/", "_")
+	key = strings.ReplaceAll(key, ":", "_")
+	return filepath.Join(c.cacheDir, key+".json")
+}
+
+func (c *CachingRegistryClient) readCache(path string) (*cachedTags, bool) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+
+	var entry cachedTags
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, false
+	}
+
+	if time.Since(entry.FetchedAt) > c.ttl {
+		return nil, false
+	}
+
+	return &entry, true
+}
+
+func (c *CachingRegistryClient) writeCache(path string, tags []TagInfo) {
+	if err := os.MkdirAll(c.cacheDir, 0755); err != nil {
+		return
+	}
+
+	entry := cachedTags{FetchedAt: time.Now(), Tags: tags}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	_ = ioutil.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,44 @@
+package packs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCachePathForIncludesRegistryAndPlatform(t *testing.T) {
+	dockerHub := &CachingRegistryClient{cacheDir: "/cache", registryURL: "https://registry-1.docker.io/", platform: "linux/amd64"}
+	ghcr := &CachingRegistryClient{cacheDir: "/cache", registryURL: "https://ghcr.io/", platform: "linux/amd64"}
+	arm := &CachingRegistryClient{cacheDir: "/cache", registryURL: "https://registry-1.docker.io/", platform: "linux/arm64"}
+
+	key := "node@18.0.0"
+	paths := map[string]string{
+		"dockerHub": dockerHub.cachePathFor(key),
+		"ghcr":      ghcr.cachePathFor(key),
+		"arm":       arm.cachePathFor(key),
+	}
+
+	if paths["dockerHub"] == paths["ghcr"] {
+		t.Errorf("cache path should differ by registry URL, both got %q", paths["dockerHub"])
+	}
+	if paths["dockerHub"] == paths["arm"] {
+		t.Errorf("cache path should differ by platform, both got %q", paths["dockerHub"])
+	}
+}
+
+func TestReadWriteCacheRoundTripAndTTL(t *testing.T) {
+	dir := t.TempDir()
+	c := NewCachingRegistryClient(nil, dir, time.Hour, "https://registry-1.docker.io/", "linux/amd64")
+
+	path := c.cachePathFor("node@18.0.0")
+	c.writeCache(path, []TagInfo{{Tag: "18.0.0", Digest: "sha256:abc"}})
+
+	cached, ok := c.readCache(path)
+	if !ok || len(cached.Tags) != 1 || cached.Tags[0].Tag != "18.0.0" {
+		t.Fatalf("got cached=%+v ok=%v, want a single 18.0.0 entry", cached, ok)
+	}
+
+	expired := NewCachingRegistryClient(nil, dir, -time.Second, "https://registry-1.docker.io/", "linux/amd64")
+	if _, ok := expired.readCache(path); ok {
+		t.Fatal("expected an entry older than ttl to be treated as a miss")
+	}
+}
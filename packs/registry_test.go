@@ -0,0 +1,18 @@
+package packs
+
+import "testing"
+
+func TestDockerHubRepo(t *testing.T) {
+	cases := map[string]string{
+		"node":           "library/node",
+		"ruby":           "library/ruby",
+		"bitnami/nginx":  "bitnami/nginx",
+		"library/python": "library/python",
+	}
+
+	for in, want := range cases {
+		if got := dockerHubRepo(in); got != want {
+			t.Errorf("dockerHubRepo(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
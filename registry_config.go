@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/mitchellh/go-homedir"
+)
+
+// dockerConfigAuth mirrors the "auths" entries of ~/.docker/config.json.
+type dockerConfigAuth struct {
+	Auth string `json:"auth"`
+}
+
+type dockerConfigFile struct {
+	Auths map[string]dockerConfigAuth `json:"auths"`
+}
+
+// dockerConfigCredentials looks up a username/password for registryURL in
+// ~/.docker/config.json, so -registry-user/-registry-password only need to
+// be passed when the registry isn't already logged into via `docker login`.
+func dockerConfigCredentials(registryURL string) (string, string) {
+	homeDir, err := homedir.Dir()
+	if err != nil {
+		return "", ""
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(homeDir, ".docker", "config.json"))
+	if err != nil {
+		return "", ""
+	}
+
+	var config dockerConfigFile
+	if err := json.Unmarshal(data, &config); err != nil {
+		return "", ""
+	}
+
+	host := registryHost(registryURL)
+	for registry, auth := range config.Auths {
+		if !strings.Contains(registry, host) {
+			continue
+		}
+
+		decoded, err := base64.StdEncoding.DecodeString(auth.Auth)
+		if err != nil {
+			continue
+		}
+
+		parts := strings.SplitN(string(decoded), ":", 2)
+		if len(parts) == 2 {
+			return parts[0], parts[1]
+		}
+	}
+
+	return "", ""
+}
+
+func registryHost(registryURL string) string {
+	if registryURL == "" {
+		return "docker.io"
+	}
+
+	host := strings.TrimPrefix(registryURL, "https://")
+	host = strings.TrimPrefix(host, "http://")
+	host = strings.TrimSuffix(host, "/")
+	return host
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/cloud66-oss/starter/common"
+	"github.com/cloud66-oss/starter/packs"
+	"github.com/mitchellh/go-homedir"
+)
+
+// stringSliceFlag collects repeated occurrences of a flag, e.g.
+// -feature foo -feature bar, into a slice.
+type stringSliceFlag []string
+
+func (s *stringSliceFlag) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringSliceFlag) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+// writeDockerfileFromTemplate renders the Dockerfile for pack using a
+// user-supplied Go text/template instead of the pack's built-in template,
+// honouring -feature, --var, --expose, --timezone and --diff.
+func writeDockerfileFromTemplate(pack packs.Pack, path string) error {
+	templateFile := flagTemplateFile
+	if templateFile == "" {
+		homeDir, err := homedir.Dir()
+		if err != nil {
+			return err
+		}
+		templateFile = filepath.Join(homeDir, ".starter", "dockerfile.tpl")
+	}
+
+	tplContent, err := ioutil.ReadFile(templateFile)
+	if err != nil {
+		return fmt.Errorf("unable to read template file %s due to %s", templateFile, err.Error())
+	}
+
+	tpl, err := template.New(filepath.Base(templateFile)).Parse(string(tplContent))
+	if err != nil {
+		return fmt.Errorf("unable to parse template file %s due to %s", templateFile, err.Error())
+	}
+
+	context, err := dockerfileTemplateContext(pack)
+	if err != nil {
+		return err
+	}
+
+	var rendered bytes.Buffer
+	if err := tpl.Execute(&rendered, context); err != nil {
+		return fmt.Errorf("unable to render template file %s due to %s", templateFile, err.Error())
+	}
+
+	dockerfilePath := filepath.Join(path, "Dockerfile")
+
+	if flagDiff {
+		existing, _ := ioutil.ReadFile(dockerfilePath)
+		printDockerfileDiff(string(existing), rendered.String())
+		return nil
+	}
+
+	return ioutil.WriteFile(dockerfilePath, rendered.Bytes(), 0644)
+}
+
+// dockerfileTemplateContext merges the values detected by the pack with the
+// user-supplied features, variables, exposed ports and timezone, so the
+// template has everything it needs without re-detecting anything itself.
+func dockerfileTemplateContext(pack packs.Pack) (map[string]interface{}, error) {
+	context := map[string]interface{}{
+		"Language":        pack.Name(),
+		"LanguageVersion": pack.LanguageVersion(),
+		// Pack doesn't expose build commands yet (analyze() hard-codes
+		// result.BuildCommands to an empty slice too), so the template
+		// context mirrors that rather than reusing the start commands.
+		"BuildCommands":   []string{},
+		"StartCommands":   pack.GetStartCommands(),
+		"Expose":          []string(flagExpose),
+		"Timezone":        flagTimezone,
+		"TimezonePackage": timezonePackageFor(pack.Name()),
+	}
+
+	features := map[string]bool{}
+	for _, feature := range flagFeatures {
+		features[feature] = true
+	}
+	context["Features"] = features
+
+	for _, raw := range flagVars {
+		key, value, ok := splitVarFlag(raw)
+		if !ok {
+			return nil, fmt.Errorf("invalid --var %q, expected key=value", raw)
+		}
+		context[key] = value
+	}
+
+	return context, nil
+}
+
+func splitVarFlag(raw string) (string, string, bool) {
+	parts := strings.SplitN(raw, "=", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+// timezonePackageFor returns the package name typically used to install
+// tzdata for a given base image family, so --timezone can add the right
+// ENV/RUN lines regardless of the detected language.
+func timezonePackageFor(language string) string {
+	switch language {
+	case "alpine":
+		return "tzdata"
+	default:
+		return "tzdata"
+	}
+}
+
+// printDockerfileDiff prints a minimal, colored line-by-line diff between an
+// existing Dockerfile (if any) and the freshly rendered one.
+func printDockerfileDiff(existing, rendered string) {
+	const (
+		red   = "\033[31m"
+		green = "\033[32m"
+		reset = "\033[0m"
+	)
+
+	existingLines := strings.Split(existing, "\n")
+	renderedLines := strings.Split(rendered, "\n")
+
+	max := len(existingLines)
+	if len(renderedLines) > max {
+		max = len(renderedLines)
+	}
+
+	for i := 0; i < max; i++ {
+		var oldLine, newLine string
+		if i < len(existingLines) {
+			oldLine = existingLines[i]
+		}
+		if i < len(renderedLines) {
+			newLine = renderedLines[i]
+		}
+
+		switch {
+		case oldLine == newLine:
+			fmt.Fprintf(os.Stdout, "  %s\n", newLine)
+		case oldLine == "":
+			fmt.Fprintf(os.Stdout, "%s+ %s%s\n", green, newLine, reset)
+		case newLine == "":
+			fmt.Fprintf(os.Stdout, "%s- %s%s\n", red, oldLine, reset)
+		default:
+			fmt.Fprintf(os.Stdout, "%s- %s%s\n", red, oldLine, reset)
+			fmt.Fprintf(os.Stdout, "%s+ %s%s\n", green, newLine, reset)
+		}
+	}
+
+	common.PrintlnL0("Diff only, Dockerfile was not written. Re-run without --diff to write it.")
+}
@@ -0,0 +1,27 @@
+package main
+
+import "testing"
+
+func TestSplitVarFlag(t *testing.T) {
+	cases := []struct {
+		raw       string
+		wantKey   string
+		wantValue string
+		wantOk    bool
+	}{
+		{"KEY=value", "KEY", "value", true},
+		{"KEY=value=with=equals", "KEY", "value=with=equals", true},
+		{"KEY=", "KEY", "", true},
+		{"novalue", "", "", false},
+		{"=value", "", "", false},
+		{"", "", "", false},
+	}
+
+	for _, c := range cases {
+		key, value, ok := splitVarFlag(c.raw)
+		if key != c.wantKey || value != c.wantValue || ok != c.wantOk {
+			t.Errorf("splitVarFlag(%q) = (%q, %q, %v), want (%q, %q, %v)",
+				c.raw, key, value, ok, c.wantKey, c.wantValue, c.wantOk)
+		}
+	}
+}